@@ -1,21 +1,46 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"app/models"
 	"app/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type PostController struct {
-	postService *services.PostService
+	postService   *services.PostService
+	uploadService *services.UploadService
 }
 
-func NewPostController(postService *services.PostService) *PostController {
-	return &PostController{postService: postService}
+func NewPostController(postService *services.PostService, uploadService *services.UploadService) *PostController {
+	return &PostController{postService: postService, uploadService: uploadService}
+}
+
+// UploadImage handles POST /api/post/upload: it streams a multipart image
+// to the configured storage backend and returns the public URL the editor
+// should reference in post content.
+func (pc *PostController) UploadImage(ctx *gin.Context) {
+	file, header, err := ctx.Request.FormFile("image")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "missing image file"})
+		return
+	}
+	defer file.Close()
+
+	url, err := pc.uploadService.Upload(file, header)
+	if err != nil {
+		ctx.JSON(http.StatusBadGateway, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "data": gin.H{"url": url}})
 }
 
 // CreatePost handles post creation
@@ -28,10 +53,13 @@ func (pc *PostController) CreatePost(ctx *gin.Context) {
 		return
 	}
 
-	newPost, err := pc.postService.CreatePost(payload, currentUser.ID)
+	newPost, err := pc.postService.CreatePost(ctx, payload, currentUser.ID)
 	if err != nil {
-		switch err.Error() {
-		case "post with that title already exists":
+		var sanitizeErr *services.SanitizeError
+		switch {
+		case errors.As(err, &sanitizeErr):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"status": "fail", "message": sanitizeErr.Error()})
+		case err.Error() == "post with that title already exists":
 			ctx.JSON(http.StatusConflict, gin.H{"status": "fail", "message": err.Error()})
 		default:
 			ctx.JSON(http.StatusBadGateway, gin.H{"status": "error", "message": err.Error()})
@@ -53,11 +81,16 @@ func (pc *PostController) UpdatePost(ctx *gin.Context) {
 		return
 	}
 
-	updatedPost, err := pc.postService.UpdatePost(postID, payload, currentUser.ID)
+	updatedPost, err := pc.postService.UpdatePost(ctx, postID, payload, currentUser.ID)
 	if err != nil {
-		switch err.Error() {
-		case "post not found":
+		var sanitizeErr *services.SanitizeError
+		switch {
+		case errors.As(err, &sanitizeErr):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"status": "fail", "message": sanitizeErr.Error()})
+		case err.Error() == "post not found":
 			ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": err.Error()})
+		case err.Error() == "forbidden":
+			ctx.JSON(http.StatusForbidden, gin.H{"status": "fail", "message": "you do not own this post"})
 		default:
 			ctx.JSON(http.StatusBadGateway, gin.H{"status": "fail", "message": err.Error()})
 		}
@@ -71,7 +104,7 @@ func (pc *PostController) UpdatePost(ctx *gin.Context) {
 func (pc *PostController) FindPostById(ctx *gin.Context) {
 	postID := ctx.Param("postId")
 
-	post, err := pc.postService.FindPostByID(postID)
+	post, err := pc.postService.FindPostByID(ctx, postID)
 	if err != nil {
 		switch err.Error() {
 		case "post not found":
@@ -85,8 +118,77 @@ func (pc *PostController) FindPostById(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": post})
 }
 
-// FindPosts handles retrieving paginated posts
+// FindPosts handles retrieving posts. It supports classic ?page&limit
+// pagination as well as cursor pagination via ?after=<cursor> (next page)
+// or ?before=<cursor> (previous page), ?limit=,
+// ?sort=created_at|title|-updated_at, ?author=<uuid>, ?q=<full-text>, and
+// ?tags=foo,bar / ?created_after= / ?created_before= filters.
 func (pc *PostController) FindPosts(ctx *gin.Context) {
+	params := models.PostListParams{
+		Limit:  10,
+		Page:   1,
+		After:  ctx.Query("after"),
+		Before: ctx.Query("before"),
+		Tags:   parseTags(ctx.Query("tags")),
+		Sort:   ctx.Query("sort"),
+		Query:  ctx.Query("q"),
+	}
+
+	if page, err := strconv.Atoi(ctx.Query("page")); err == nil && page > 0 {
+		params.Page = page
+	}
+
+	if limit, err := strconv.Atoi(ctx.Query("limit")); err == nil && limit > 0 {
+		params.Limit = limit
+	}
+
+	if raw := ctx.Query("author"); raw != "" {
+		authorID, err := uuid.Parse(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "invalid author id"})
+			return
+		}
+		params.Author = &authorID
+	}
+
+	if raw := ctx.Query("created_after"); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "invalid created_after"})
+			return
+		}
+		params.CreatedAfter = &t
+	}
+
+	if raw := ctx.Query("created_before"); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "invalid created_before"})
+			return
+		}
+		params.CreatedBefore = &t
+	}
+
+	result, err := pc.postService.FindPosts(ctx, params)
+	if err != nil {
+		ctx.JSON(http.StatusBadGateway, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":      "success",
+		"data":        result.Posts,
+		"next_cursor": result.NextCursor,
+		"prev_cursor": result.PrevCursor,
+		"total":       result.Total,
+	})
+}
+
+// GetPostsByTag handles GET /api/post/tag/:name, returning posts carrying
+// the given (optionally namespaced) tag.
+func (pc *PostController) GetPostsByTag(ctx *gin.Context) {
+	tagName := ctx.Param("name")
+
 	var page = ctx.DefaultQuery("page", "1")
 	var limit = ctx.DefaultQuery("limit", "10")
 
@@ -100,24 +202,97 @@ func (pc *PostController) FindPosts(ctx *gin.Context) {
 		intLimit = 10
 	}
 
-	posts, err := pc.postService.FindPosts(intPage, intLimit)
+	result, err := pc.postService.GetPostsByTag(ctx, tagName, intPage, intLimit)
 	if err != nil {
 		ctx.JSON(http.StatusBadGateway, gin.H{"status": "error", "message": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"status": "success", "results": len(posts), "data": posts})
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":       "success",
+		"results":      len(result.Posts),
+		"data":         result.Posts,
+		"current_page": result.CurrentPage,
+		"total_pages":  result.TotalPages,
+	})
+}
+
+// AttachTags handles POST /api/post/:postId/tags.
+func (pc *PostController) AttachTags(ctx *gin.Context) {
+	postID := ctx.Param("postId")
+
+	var payload *models.AttachTagsRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": err.Error()})
+		return
+	}
+
+	post, err := pc.postService.AttachTags(ctx, postID, payload.Tags)
+	if err != nil {
+		switch err.Error() {
+		case "post not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": err.Error()})
+		default:
+			ctx.JSON(http.StatusBadGateway, gin.H{"status": "error", "message": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": post})
+}
+
+// DetachTags handles DELETE /api/post/:postId/tags.
+func (pc *PostController) DetachTags(ctx *gin.Context) {
+	postID := ctx.Param("postId")
+
+	var payload *models.AttachTagsRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": err.Error()})
+		return
+	}
+
+	if err := pc.postService.DetachTags(ctx, postID, payload.Tags); err != nil {
+		switch err.Error() {
+		case "post not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": err.Error()})
+		default:
+			ctx.JSON(http.StatusBadGateway, gin.H{"status": "error", "message": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// parseTags splits a comma-separated ?tags= query value, dropping blanks.
+func parseTags(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+
+	parts := strings.Split(raw, ",")
+	tags := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			tags = append(tags, p)
+		}
+	}
+
+	return tags
 }
 
 // DeletePost handles post deletion
 func (pc *PostController) DeletePost(ctx *gin.Context) {
 	postID := ctx.Param("postId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
 
-	err := pc.postService.DeletePost(postID)
+	err := pc.postService.DeletePost(ctx, postID, currentUser.ID)
 	if err != nil {
 		switch err.Error() {
 		case "post not found":
 			ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": err.Error()})
+		case "forbidden":
+			ctx.JSON(http.StatusForbidden, gin.H{"status": "fail", "message": "you do not own this post"})
 		default:
 			ctx.JSON(http.StatusBadGateway, gin.H{"status": "error", "message": err.Error()})
 		}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"app/models"
+	"app/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PostsAdmin exposes moderation endpoints for the "admin" role only; it is
+// mounted behind middleware.AuthMiddleware()+middleware.AdminMiddleware().
+type PostsAdmin struct {
+	postService *services.PostService
+}
+
+func NewPostsAdmin(postService *services.PostService) *PostsAdmin {
+	return &PostsAdmin{postService: postService}
+}
+
+// ListPosts returns every post regardless of owner, for moderation review.
+func (pa *PostsAdmin) ListPosts(ctx *gin.Context) {
+	var page = ctx.DefaultQuery("page", "1")
+	var limit = ctx.DefaultQuery("limit", "10")
+
+	intPage, err := strconv.Atoi(page)
+	if err != nil || intPage < 1 {
+		intPage = 1
+	}
+
+	intLimit, err := strconv.Atoi(limit)
+	if err != nil || intLimit < 1 {
+		intLimit = 10
+	}
+
+	result, err := pa.postService.FindPosts(ctx, models.PostListParams{Page: intPage, Limit: intLimit})
+	if err != nil {
+		ctx.JSON(http.StatusBadGateway, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "results": len(result.Posts), "data": result})
+}
+
+// ModeratePost removes a reported post regardless of who owns it.
+func (pa *PostsAdmin) ModeratePost(ctx *gin.Context) {
+	postID := ctx.Param("postId")
+
+	if err := pa.postService.ForceDeletePost(ctx, postID); err != nil {
+		switch err.Error() {
+		case "post not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": err.Error()})
+		default:
+			ctx.JSON(http.StatusBadGateway, gin.H{"status": "error", "message": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,68 @@
+package initializers
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds every environment-driven setting the app needs, loaded once
+// at startup via LoadConfig.
+type Config struct {
+	DBHost         string `mapstructure:"POSTGRES_HOST"`
+	DBUserName     string `mapstructure:"POSTGRES_USER"`
+	DBUserPassword string `mapstructure:"POSTGRES_PASSWORD"`
+	DBName         string `mapstructure:"POSTGRES_DB"`
+	DBPort         string `mapstructure:"POSTGRES_PORT"`
+	ServerPort     string `mapstructure:"PORT"`
+
+	ClientOrigin string `mapstructure:"CLIENT_ORIGIN"`
+
+	AccessTokenPrivateKey  string        `mapstructure:"ACCESS_TOKEN_PRIVATE_KEY"`
+	AccessTokenPublicKey   string        `mapstructure:"ACCESS_TOKEN_PUBLIC_KEY"`
+	RefreshTokenPrivateKey string        `mapstructure:"REFRESH_TOKEN_PRIVATE_KEY"`
+	RefreshTokenPublicKey  string        `mapstructure:"REFRESH_TOKEN_PUBLIC_KEY"`
+	AccessTokenExpiresIn   time.Duration `mapstructure:"ACCESS_TOKEN_EXPIRED_IN"`
+	RefreshTokenExpiresIn  time.Duration `mapstructure:"REFRESH_TOKEN_EXPIRED_IN"`
+	AccessTokenMaxAge      int           `mapstructure:"ACCESS_TOKEN_MAXAGE"`
+	RefreshTokenMaxAge     int           `mapstructure:"REFRESH_TOKEN_MAXAGE"`
+
+	// AllowedImageHost is the only host post content's <img src> may
+	// reference; it should match whatever UploadPublicBaseURL resolves to.
+	AllowedImageHost string `mapstructure:"ALLOWED_IMAGE_HOST"`
+
+	// Upload* configure the image upload pipeline backing
+	// POST /api/post/upload. UploadBackend is either "local" or "s3".
+	UploadBackend       string `mapstructure:"UPLOAD_BACKEND"`
+	UploadLocalDir      string `mapstructure:"UPLOAD_LOCAL_DIR"`
+	UploadPublicBaseURL string `mapstructure:"UPLOAD_PUBLIC_BASE_URL"`
+	S3Bucket            string `mapstructure:"S3_BUCKET"`
+	S3Region            string `mapstructure:"S3_REGION"`
+	S3Endpoint          string `mapstructure:"S3_ENDPOINT"`
+	S3AccessKey         string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey         string `mapstructure:"S3_SECRET_KEY"`
+}
+
+// Cfg is the process-wide config loaded by LoadConfig, kept so packages
+// like middleware that run outside main's init don't need it threaded
+// through every call site.
+var Cfg Config
+
+// LoadConfig reads app.env in path into a Config, with environment variables
+// of the same name overriding its values. It returns an error when app.env
+// cannot be read.
+func LoadConfig(path string) (config Config, err error) {
+	viper.AddConfigPath(path)
+	viper.SetConfigType("env")
+	viper.SetConfigName("app")
+	viper.AutomaticEnv()
+
+	err = viper.ReadInConfig()
+	if err != nil {
+		return
+	}
+
+	err = viper.Unmarshal(&config)
+	Cfg = config
+	return
+}
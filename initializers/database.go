@@ -0,0 +1,26 @@
+package initializers
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// DB is the process-wide database handle, set up by ConnectDB.
+var DB *gorm.DB
+
+// ConnectDB opens the Postgres connection used by every service.
+func ConnectDB(config *Config) {
+	var err error
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Shanghai",
+		config.DBHost, config.DBUserName, config.DBUserPassword, config.DBName, config.DBPort)
+
+	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		log.Fatal("Failed to connect to the database: ", err)
+	}
+
+	log.Println("🚀 Connected successfully to the database")
+}
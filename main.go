@@ -6,6 +6,7 @@ import (
 
 	"app/controllers"
 	"app/initializers"
+	"app/middleware"
 	"app/routes"
 	"app/services"
 
@@ -37,6 +38,7 @@ func init() {
 	authService := services.NewAuthService(initializers.DB)
 	userService := services.NewUserService(initializers.DB)
 	postService := services.NewPostService(initializers.DB)
+	uploadService := services.NewUploadService(&config)
 
 	// Initialize Controllers with Services
 	AuthController = controllers.NewAuthController(authService)
@@ -45,8 +47,9 @@ func init() {
 	UserController = controllers.NewUserController(userService)
 	UserRouteController = routes.NewRouteUserController(UserController)
 
-	PostController = controllers.NewPostController(postService)
-	PostRouteController = routes.NewRoutePostController(PostController)
+	PostController = controllers.NewPostController(postService, uploadService)
+	PostsAdmin := controllers.NewPostsAdmin(postService)
+	PostRouteController = routes.NewRoutePostController(PostController, PostsAdmin)
 
 	server = gin.Default()
 }
@@ -62,6 +65,8 @@ func main() {
 	corsConfig.AllowCredentials = true
 
 	server.Use(cors.New(corsConfig))
+	server.Use(middleware.RequestLogger())
+	server.Use(middleware.Recovery())
 
 	router := server.Group("/api")
 	router.GET("/healthchecker", func(ctx *gin.Context) {
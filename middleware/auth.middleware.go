@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"app/initializers"
+	"app/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// AuthMiddleware verifies the bearer/cookie JWT, loads the corresponding
+// user, and stores it on the context as "currentUser" for downstream
+// handlers (and for PostService ownership checks).
+func AuthMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := extractToken(ctx)
+		if token == "" {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"status": "fail", "message": "you are not logged in"})
+			return
+		}
+
+		userID, err := validateToken(token)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"status": "fail", "message": err.Error()})
+			return
+		}
+
+		var user models.User
+		if result := initializers.DB.First(&user, "id = ?", userID); result.Error != nil {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"status": "fail", "message": "the user belonging to this token no longer exists"})
+			return
+		}
+
+		ctx.Set("currentUser", user)
+
+		// Re-attach the request-scoped logger with user_id so later log
+		// lines for this request (e.g. PostService errors) carry it.
+		logger := LoggerFromContext(ctx).With().Str("user_id", user.ID.String()).Logger()
+		ctx.Set("logger", &logger)
+		ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), loggerCtxKey{}, &logger))
+
+		ctx.Next()
+	}
+}
+
+// AdminMiddleware builds on AuthMiddleware and rejects any request whose
+// currentUser does not carry the "admin" role.
+func AdminMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		currentUser := ctx.MustGet("currentUser").(models.User)
+		if currentUser.Role != "admin" {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"status": "fail", "message": "you do not have permission to perform this action"})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func extractToken(ctx *gin.Context) string {
+	authorizationHeader := ctx.Request.Header.Get("Authorization")
+	if fields := strings.Fields(authorizationHeader); len(fields) == 2 && fields[0] == "Bearer" {
+		return fields[1]
+	}
+
+	if cookie, err := ctx.Cookie("token"); err == nil {
+		return cookie
+	}
+
+	return ""
+}
+
+func validateToken(token string) (string, error) {
+	decodedPublicKey, err := base64.StdEncoding.DecodeString(initializers.Cfg.AccessTokenPublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(decodedPublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return publicKey, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sub, ok := claims["sub"].(string)
+	if !ok {
+		return "", jwt.ErrTokenInvalidClaims
+	}
+
+	return sub, nil
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"app/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+type loggerCtxKey struct{}
+
+// RequestLogger attaches a per-request zerolog.Logger carrying a request
+// ID, the current user (once AuthMiddleware has run) and latency/response
+// size once the handler returns, so PostService errors logged through
+// LoggerFromContext correlate with the request that triggered them.
+func RequestLogger() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		requestID := uuid.New().String()
+
+		logger := log.With().Str("request_id", requestID).Logger()
+		ctx.Set("logger", &logger)
+		ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), loggerCtxKey{}, &logger))
+		ctx.Header("X-Request-Id", requestID)
+
+		ctx.Next()
+
+		event := logger.Info()
+		if user, ok := ctx.Get("currentUser"); ok {
+			if currentUser, ok := user.(models.User); ok {
+				event = event.Str("user_id", currentUser.ID.String())
+			}
+		}
+
+		event.
+			Str("method", ctx.Request.Method).
+			Str("path", ctx.Request.URL.Path).
+			Int("status", ctx.Writer.Status()).
+			Int("response_size", ctx.Writer.Size()).
+			Dur("latency", time.Since(start)).
+			Msg("request handled")
+	}
+}
+
+// LoggerFromContext returns the request-scoped logger RequestLogger
+// attached to ctx, falling back to the global logger outside a request
+// (e.g. in tests). ctx may be the request's context or the *gin.Context
+// itself; the latter resolves the "logger" key set on it.
+func LoggerFromContext(ctx context.Context) *zerolog.Logger {
+	if logger, ok := ctx.Value(loggerCtxKey{}).(*zerolog.Logger); ok {
+		return logger
+	}
+	if logger, ok := ctx.Value("logger").(*zerolog.Logger); ok {
+		return logger
+	}
+
+	return &log.Logger
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"app/models"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// limiterIdleTTL bounds how long an idle caller's limiter is kept around.
+// Without this, limiterRegistry.limiters would grow by one entry per
+// distinct user/IP forever and never shrink.
+const limiterIdleTTL = 10 * time.Minute
+
+// limiterEntry pairs a token bucket with the last time it was used, so
+// sweep() can tell which entries are safe to evict.
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// limiterRegistry hands out one token bucket per key (currentUser.ID when
+// authenticated, client IP otherwise) so limits are tracked independently
+// per caller instead of globally. Entries idle past limiterIdleTTL are
+// swept periodically.
+type limiterRegistry struct {
+	mu       sync.Mutex
+	limiters map[string]*limiterEntry
+	r        rate.Limit
+	burst    int
+}
+
+func newLimiterRegistry(requestsPerMinute int) *limiterRegistry {
+	lr := &limiterRegistry{
+		limiters: make(map[string]*limiterEntry),
+		r:        rate.Every(time.Minute / time.Duration(requestsPerMinute)),
+		burst:    requestsPerMinute,
+	}
+
+	go lr.sweepLoop()
+
+	return lr
+}
+
+func (lr *limiterRegistry) get(key string) *rate.Limiter {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	entry, ok := lr.limiters[key]
+	if !ok {
+		entry = &limiterEntry{limiter: rate.NewLimiter(lr.r, lr.burst)}
+		lr.limiters[key] = entry
+	}
+	entry.lastSeen = time.Now()
+
+	return entry.limiter
+}
+
+// sweepLoop periodically evicts limiters that have gone idle, bounding
+// registry size on an always-on server.
+func (lr *limiterRegistry) sweepLoop() {
+	ticker := time.NewTicker(limiterIdleTTL)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		lr.sweep()
+	}
+}
+
+func (lr *limiterRegistry) sweep() {
+	cutoff := time.Now().Add(-limiterIdleTTL)
+
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	for key, entry := range lr.limiters {
+		if entry.lastSeen.Before(cutoff) {
+			delete(lr.limiters, key)
+		}
+	}
+}
+
+// RateLimit builds a token-bucket limiter allowing requestsPerMinute
+// requests per caller. Callers are keyed by currentUser.ID when
+// AuthMiddleware has already populated the context, or by client IP
+// otherwise. Throttled requests get a 429 with Retry-After.
+func RateLimit(requestsPerMinute int) gin.HandlerFunc {
+	registry := newLimiterRegistry(requestsPerMinute)
+
+	return func(ctx *gin.Context) {
+		limiter := registry.get(rateLimitKey(ctx))
+
+		if !limiter.Allow() {
+			ctx.Header("Retry-After", "60")
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"status":  "fail",
+				"message": "rate limit exceeded, try again later",
+			})
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func rateLimitKey(ctx *gin.Context) string {
+	if user, ok := ctx.Get("currentUser"); ok {
+		if currentUser, ok := user.(models.User); ok {
+			return fmt.Sprintf("user:%s", currentUser.ID)
+		}
+	}
+
+	return fmt.Sprintf("ip:%s", ctx.ClientIP())
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery converts a panic in a later handler into a 500 response using
+// the app's usual {status, message} envelope instead of gin's default
+// plain-text/HTML output.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(ctx *gin.Context, recovered any) {
+		message := "internal server error"
+		if err, ok := recovered.(error); ok {
+			message = err.Error()
+		}
+
+		LoggerFromContext(ctx.Request.Context()).Error().Interface("panic", recovered).Msg("recovered from panic")
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": message})
+	})
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Post represents a single post owned by a user.
+type Post struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	Title     string    `gorm:"uniqueIndex;not null" json:"title"`
+	Content   string    `gorm:"not null" json:"content"`
+	Image     string    `json:"image"`
+	User      uuid.UUID `gorm:"type:uuid;not null" json:"user"`
+	Tags      []Tag     `gorm:"many2many:post_tags;" json:"tags,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreatePostRequest is the payload accepted by PostController.CreatePost.
+type CreatePostRequest struct {
+	Title   string `json:"title" binding:"required"`
+	Content string `json:"content" binding:"required"`
+	Image   string `json:"image"`
+}
+
+// UpdatePost is the payload accepted by PostController.UpdatePost.
+type UpdatePost struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	Image   string `json:"image"`
+}
+
+// PostListParams captures every filter/sort/pagination option
+// PostController.FindPosts accepts. Page/Limit drive classic OFFSET
+// pagination; After/Before (opaque cursors) take priority when set, with
+// After taking priority over Before if both are somehow present.
+type PostListParams struct {
+	Page          int
+	Limit         int
+	After         string
+	Before        string
+	Tags          []string
+	Sort          string
+	Author        *uuid.UUID
+	Query         string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+// PostListResult is the envelope FindPosts returns: {data, next_cursor,
+// prev_cursor, total}. NextCursor/PrevCursor are only populated when a
+// further page actually exists in that direction.
+type PostListResult struct {
+	Posts      []Post `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Total      int64  `json:"total"`
+}
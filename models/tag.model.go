@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tag labels a post, optionally within a booru-style namespace (e.g.
+// "character:name") captured by Type so lookups can filter by prefix.
+type Tag struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	Name      string    `gorm:"uniqueIndex:idx_tag_name_type;not null" json:"name"`
+	Type      string    `gorm:"uniqueIndex:idx_tag_name_type;not null;default:'general'" json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName pins the table name to "tags" explicitly (the same name gorm's
+// default pluralization would pick) so it does not depend on that guess.
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// AttachTagsRequest is the payload accepted by
+// PostController.AttachTags (POST /api/post/:postId/tags).
+type AttachTagsRequest struct {
+	Tags []string `json:"tags" binding:"required"`
+}
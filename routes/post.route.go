@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"app/controllers"
+	"app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PostRouteController struct {
+	postController *controllers.PostController
+	postsAdmin     *controllers.PostsAdmin
+}
+
+func NewRoutePostController(postController *controllers.PostController, postsAdmin *controllers.PostsAdmin) PostRouteController {
+	return PostRouteController{postController, postsAdmin}
+}
+
+// readsPerMinute bounds every /post request per client IP (it runs before
+// AuthMiddleware, so no currentUser is set yet); writesPerMinute further
+// bounds the authenticated endpoints per currentUser.ID.
+const (
+	readsPerMinute  = 60
+	writesPerMinute = 5
+)
+
+// PostRoute registers the post endpoints under the given router group,
+// splitting unauthenticated reads from an authenticated write group and an
+// admin-only moderation group. The read limit applies to every route here;
+// the write group adds its own limit on top, the admin group does not.
+func (pc *PostRouteController) PostRoute(rg *gin.RouterGroup) {
+	router := rg.Group("/post")
+	router.Use(middleware.RateLimit(readsPerMinute))
+
+	router.GET("/", pc.postController.FindPosts)
+	router.GET("/tag/:name", pc.postController.GetPostsByTag)
+	router.GET("/:postId", pc.postController.FindPostById)
+
+	authorized := router.Group("/")
+	authorized.Use(middleware.AuthMiddleware(), middleware.RateLimit(writesPerMinute))
+	authorized.POST("/create", pc.postController.CreatePost)
+	authorized.POST("/upload", pc.postController.UploadImage)
+	authorized.PUT("/:postId", pc.postController.UpdatePost)
+	authorized.DELETE("/:postId", pc.postController.DeletePost)
+	authorized.POST("/:postId/tags", pc.postController.AttachTags)
+	authorized.DELETE("/:postId/tags", pc.postController.DetachTags)
+
+	admin := router.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	admin.GET("/", pc.postsAdmin.ListPosts)
+	admin.DELETE("/:postId", pc.postsAdmin.ModeratePost)
+}
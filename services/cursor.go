@@ -0,0 +1,43 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// encodeCursor packs a (sort value, id) pair into the opaque cursor used by
+// FindPosts' ?after= pagination, so listings stay stable under concurrent
+// inserts instead of relying on OFFSET. value is the sort column's value
+// rendered as text (RFC3339Nano for the timestamp columns, the raw string
+// for "title").
+func encodeCursor(value string, id uuid.UUID) string {
+	raw := fmt.Sprintf("%s|%s", value, id.String())
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor reverses encodeCursor, returning an error for a cursor that
+// is not valid base64 or does not end in "|<uuid>". Cursors are not signed,
+// so a well-formed cursor built elsewhere is accepted.
+func decodeCursor(cursor string) (string, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", uuid.Nil, errors.New("invalid cursor")
+	}
+
+	// Split at the last "|": the id never contains one, the value may.
+	sep := strings.LastIndex(string(raw), "|")
+	if sep < 0 {
+		return "", uuid.Nil, errors.New("invalid cursor")
+	}
+
+	id, err := uuid.Parse(string(raw[sep+1:]))
+	if err != nil {
+		return "", uuid.Nil, errors.New("invalid cursor")
+	}
+
+	return string(raw[:sep]), id, nil
+}
@@ -1,11 +1,14 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"app/initializers"
+	"app/middleware"
 	"app/models"
 
 	"github.com/google/uuid"
@@ -13,19 +16,34 @@ import (
 )
 
 type PostService struct {
-	DB *gorm.DB
+	DB        *gorm.DB
+	sanitizer *ContentSanitizer
 }
 
 func NewPostService(db *gorm.DB) *PostService {
-	return &PostService{DB: db}
+	return &PostService{DB: db, sanitizer: NewContentSanitizer(initializers.Cfg.AllowedImageHost, initializers.Cfg.UploadPublicBaseURL)}
 }
 
-// CreatePost creates a new post
-func (s *PostService) CreatePost(payload *models.CreatePostRequest, userID uuid.UUID) (*models.Post, error) {
+// logError logs an internal failure through the request-scoped logger
+// RequestLogger attached to ctx, so it carries that request's request_id
+// (and user_id, once set) instead of going to the global logger untagged.
+func logError(ctx context.Context, err error, msg string) {
+	middleware.LoggerFromContext(ctx).Error().Err(err).Msg(msg)
+}
+
+// CreatePost creates a new post. Content is run through the configured
+// ContentSanitizer; disallowed tags or image hosts are rejected with a
+// *SanitizeError rather than silently stripped.
+func (s *PostService) CreatePost(ctx context.Context, payload *models.CreatePostRequest, userID uuid.UUID) (*models.Post, error) {
+	cleanContent, err := s.sanitizer.Sanitize(payload.Content)
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 	newPost := models.Post{
 		Title:     payload.Title,
-		Content:   payload.Content,
+		Content:   cleanContent,
 		Image:     payload.Image,
 		User:      userID,
 		CreatedAt: now,
@@ -37,81 +55,533 @@ func (s *PostService) CreatePost(payload *models.CreatePostRequest, userID uuid.
 		if strings.Contains(result.Error.Error(), "duplicate key") {
 			return nil, errors.New("post with that title already exists")
 		}
+		logError(ctx, result.Error, "failed to create post")
 		return nil, fmt.Errorf("failed to create post: %w", result.Error)
 	}
 
 	return &newPost, nil
 }
 
-// UpdatePost updates an existing post
-func (s *PostService) UpdatePost(postID string, payload *models.UpdatePost, userID uuid.UUID) (*models.Post, error) {
+// UpdatePost updates an existing post. Only the post's owner or a user
+// with the "admin" role may perform the update.
+func (s *PostService) UpdatePost(ctx context.Context, postID string, payload *models.UpdatePost, userID uuid.UUID) (*models.Post, error) {
 	var existingPost models.Post
 	result := s.DB.First(&existingPost, "id = ?", postID)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, errors.New("post not found")
 		}
+		logError(ctx, result.Error, "failed to fetch post")
 		return nil, fmt.Errorf("failed to fetch post: %w", result.Error)
 	}
 
-	// Optional: Add authorization check
-	// if existingPost.User != userID {
-	//     return nil, errors.New("unauthorized to update this post")
-	// }
+	if err := s.authorizeOwner(existingPost, userID); err != nil {
+		return nil, err
+	}
+
+	cleanContent, err := s.sanitizer.Sanitize(payload.Content)
+	if err != nil {
+		return nil, err
+	}
 
 	now := time.Now()
 	updatedData := models.Post{
 		Title:     payload.Title,
-		Content:   payload.Content,
+		Content:   cleanContent,
 		Image:     payload.Image,
-		User:      userID,
 		UpdatedAt: now,
 	}
 
 	result = s.DB.Model(&existingPost).Updates(updatedData)
 	if result.Error != nil {
+		logError(ctx, result.Error, "failed to update post")
 		return nil, fmt.Errorf("failed to update post: %w", result.Error)
 	}
 
 	return &existingPost, nil
 }
 
+// ForceDeletePost deletes a post without an ownership check. Callers must
+// have already authorized the request (e.g. via middleware.AdminMiddleware).
+func (s *PostService) ForceDeletePost(ctx context.Context, postID string) error {
+	result := s.DB.Delete(&models.Post{}, "id = ?", postID)
+	if result.Error != nil {
+		logError(ctx, result.Error, "failed to delete post")
+		return fmt.Errorf("failed to delete post: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("post not found")
+	}
+
+	return nil
+}
+
+// authorizeOwner returns an error unless userID owns the post or holds the
+// admin role.
+func (s *PostService) authorizeOwner(post models.Post, userID uuid.UUID) error {
+	if post.User == userID {
+		return nil
+	}
+
+	var requester models.User
+	if result := s.DB.First(&requester, "id = ?", userID); result.Error != nil {
+		return errors.New("forbidden")
+	}
+
+	if requester.Role != "admin" {
+		return errors.New("forbidden")
+	}
+
+	return nil
+}
+
 // FindPostByID retrieves a post by ID
-func (s *PostService) FindPostByID(postID string) (*models.Post, error) {
+func (s *PostService) FindPostByID(ctx context.Context, postID string) (*models.Post, error) {
 	var post models.Post
 	result := s.DB.First(&post, "id = ?", postID)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, errors.New("post not found")
 		}
+		logError(ctx, result.Error, "failed to fetch post")
 		return nil, fmt.Errorf("failed to fetch post: %w", result.Error)
 	}
 
 	return &post, nil
 }
 
-// FindPosts retrieves a paginated list of posts
-func (s *PostService) FindPosts(page, limit int) ([]models.Post, error) {
+// sortableColumns whitelists the columns ?sort= may reference, so the
+// value can't be used to inject arbitrary SQL into ORDER BY.
+var sortableColumns = map[string]struct{}{
+	"created_at": {},
+	"updated_at": {},
+	"title":      {},
+}
+
+// defaultSort is applied when params.Sort is empty.
+const defaultSort = "-created_at"
+
+// FindPosts retrieves posts matching params, preloading their tags. It
+// supports classic page/limit pagination as well as bidirectional cursor
+// pagination: After fetches the page following the cursor, Before fetches
+// the page preceding it (both encode (sort column, id) so results stay
+// stable under concurrent inserts). After takes priority over Before, and
+// either takes priority over Page, when more than one is set.
+func (s *PostService) FindPosts(ctx context.Context, params models.PostListParams) (*models.PostListResult, error) {
+	limit := params.Limit
+	if limit < 1 {
+		limit = 10
+	}
+
+	column, desc, err := parseSort(params.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	base := s.filteredPostsQuery(params)
+
+	// filteredPostsQuery may carry a GROUP BY/HAVING (when ?tags= is set),
+	// so Count() can't be called on it directly - GORM would report the
+	// size of the first group rather than the number of matching posts.
+	// Counting rows of a subquery sidesteps that regardless of grouping.
+	var total int64
+	countQuery := base.Session(&gorm.Session{}).Select("posts.id")
+	if err := s.DB.Table("(?) as grouped_posts", countQuery).Count(&total).Error; err != nil {
+		logError(ctx, err, "failed to count posts")
+		return nil, fmt.Errorf("failed to count posts: %w", err)
+	}
+
+	usingAfter := params.After != ""
+	backward := !usingAfter && params.Before != ""
+
+	// Paging backward walks the result set in the opposite direction so it
+	// can stop at the limit closest to the cursor, then the fetched page
+	// is reversed back into the normal order below.
+	queryDesc := desc
+	if backward {
+		queryDesc = !desc
+	}
+
+	query := base.Preload("Tags").Order(orderClause(column, queryDesc)).Limit(limit + 1)
+
+	usingCursor := usingAfter || backward
+	if usingCursor {
+		cursor := params.After
+		if backward {
+			cursor = params.Before
+		}
+
+		cursorValue, cursorID, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		cursorArg, err := parseCursorValue(column, cursorValue)
+		if err != nil {
+			return nil, err
+		}
+
+		op := "<"
+		if !desc {
+			op = ">"
+		}
+		if backward {
+			op = reverseOp(op)
+		}
+		query = query.Where(fmt.Sprintf("(posts.%s, posts.id) %s (?, ?)", column, op), cursorArg, cursorID)
+	} else if params.Page > 1 {
+		query = query.Offset((params.Page - 1) * limit)
+	}
+
+	var posts []models.Post
+	if result := query.Find(&posts); result.Error != nil {
+		logError(ctx, result.Error, "failed to fetch posts")
+		return nil, fmt.Errorf("failed to fetch posts: %w", result.Error)
+	}
+
+	result := &models.PostListResult{Total: total}
+
+	hasMore := len(posts) > limit
+	if hasMore {
+		posts = posts[:limit]
+	}
+	if backward {
+		reversePosts(posts)
+	}
+	result.Posts = posts
+
+	if len(posts) > 0 {
+		first, last := posts[0], posts[len(posts)-1]
+		if backward {
+			result.NextCursor = encodeCursor(postSortValue(last, column), last.ID)
+			if hasMore {
+				result.PrevCursor = encodeCursor(postSortValue(first, column), first.ID)
+			}
+		} else {
+			if hasMore {
+				result.NextCursor = encodeCursor(postSortValue(last, column), last.ID)
+			}
+			if usingCursor {
+				result.PrevCursor = encodeCursor(postSortValue(first, column), first.ID)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// reverseOp flips a keyset comparison operator, used to walk a backward
+// page in the direction that stops at the limit closest to the cursor.
+func reverseOp(op string) string {
+	if op == "<" {
+		return ">"
+	}
+	return "<"
+}
+
+// reversePosts reverses posts in place, used to restore a backward page
+// (fetched in the opposite order so it stops at the cursor) to the
+// caller's expected order.
+func reversePosts(posts []models.Post) {
+	for i, j := 0, len(posts)-1; i < j; i, j = i+1, j-1 {
+		posts[i], posts[j] = posts[j], posts[i]
+	}
+}
+
+// postSortValue renders the value of column for post as the text stored in
+// the cursor: RFC3339Nano for the timestamp columns, the raw string for
+// "title".
+func postSortValue(post models.Post, column string) string {
+	switch column {
+	case "updated_at":
+		return post.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case "title":
+		return post.Title
+	default:
+		return post.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// parseCursorValue converts a cursor's text value back into the type
+// column needs for comparison: a time.Time for the timestamp columns, or
+// the string itself for "title".
+func parseCursorValue(column, value string) (any, error) {
+	if column == "title" {
+		return value, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	return t, nil
+}
+
+// parseSort splits a "-field" sort expression into its column and
+// direction, rejecting anything outside sortableColumns.
+func parseSort(sort string) (column string, desc bool, err error) {
+	if sort == "" {
+		sort = defaultSort
+	}
+
+	desc = strings.HasPrefix(sort, "-")
+	column = strings.TrimPrefix(sort, "-")
+
+	if _, ok := sortableColumns[column]; !ok {
+		return "", false, fmt.Errorf("invalid sort field: %s", column)
+	}
+
+	return column, desc, nil
+}
+
+func orderClause(column string, desc bool) string {
+	dir := "ASC"
+	if desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf("posts.%s %s, posts.id %s", column, dir, dir)
+}
+
+// filteredPostsQuery applies every non-pagination filter in params: tags,
+// author, full-text search and created_at bounds.
+func (s *PostService) filteredPostsQuery(params models.PostListParams) *gorm.DB {
+	query := s.DB.Model(&models.Post{})
+	query = filterByTags(query, params.Tags)
+
+	if params.Author != nil {
+		query = query.Where("posts.user = ?", *params.Author)
+	}
+
+	if params.Query != "" {
+		query = query.Where("to_tsvector('english', posts.title || ' ' || posts.content) @@ plainto_tsquery('english', ?)", params.Query)
+	}
+
+	if params.CreatedAfter != nil {
+		query = query.Where("posts.created_at >= ?", *params.CreatedAfter)
+	}
+
+	if params.CreatedBefore != nil {
+		query = query.Where("posts.created_at <= ?", *params.CreatedBefore)
+	}
+
+	return query
+}
+
+// filterByTags joins post_tags so that query only matches posts having
+// every tag in tagNames. Names are read the way findOrCreateTags stores
+// them ("character:name", or "general" type without a prefix). It is a
+// no-op when tagNames is empty.
+func filterByTags(query *gorm.DB, tagNames []string) *gorm.DB {
+	if len(tagNames) == 0 {
+		return query
+	}
+
+	seen := make(map[string]struct{}, len(tagNames))
+	conds := make([]string, 0, len(tagNames))
+	args := make([]any, 0, 2*len(tagNames))
+	for _, raw := range tagNames {
+		typ, name := "general", raw
+		if t, n, ok := strings.Cut(raw, ":"); ok {
+			typ, name = t, n
+		}
+		if _, dup := seen[typ+":"+name]; dup {
+			continue
+		}
+		seen[typ+":"+name] = struct{}{}
+		conds = append(conds, "(tags.type = ? AND tags.name = ?)")
+		args = append(args, typ, name)
+	}
+
+	return query.
+		Joins("JOIN post_tags ON post_tags.post_id = posts.id").
+		Joins("JOIN tags ON tags.id = post_tags.tag_id").
+		Where("("+strings.Join(conds, " OR ")+")", args...).
+		Group("posts.id").
+		Having("COUNT(DISTINCT tags.id) = ?", len(conds))
+}
+
+// PostsByTagResult is the envelope GetPostsByTag returns: the matching
+// page of posts alongside the page metadata CountPostPages computes from
+// the total number of posts carrying the tag.
+type PostsByTagResult struct {
+	Posts       []models.Post
+	CurrentPage int
+	TotalPages  int
+}
+
+// GetPostsByTag retrieves a paginated list of posts carrying a tag. The
+// tag may be namespaced booru-style ("character:name"), in which case the
+// match is scoped to that type and the name is treated as a prefix.
+func (s *PostService) GetPostsByTag(ctx context.Context, tagName string, page, limit int) (*PostsByTagResult, error) {
 	offset := (page - 1) * limit
 
+	tagQuery := s.postTagQuery(tagName)
+
+	var total int64
+	countQuery := s.DB.Model(&models.Post{}).
+		Select("posts.id").
+		Joins("JOIN post_tags ON post_tags.post_id = posts.id").
+		Where("post_tags.tag_id IN (?)", tagQuery).
+		Group("posts.id")
+	if err := s.DB.Table("(?) as grouped_posts", countQuery).Count(&total).Error; err != nil {
+		logError(ctx, err, "failed to count posts by tag")
+		return nil, fmt.Errorf("failed to count posts by tag: %w", err)
+	}
+
 	var posts []models.Post
-	result := s.DB.Limit(limit).Offset(offset).Find(&posts)
+	result := s.DB.Model(&models.Post{}).
+		Preload("Tags").
+		Joins("JOIN post_tags ON post_tags.post_id = posts.id").
+		Where("post_tags.tag_id IN (?)", tagQuery).
+		Group("posts.id").
+		Limit(limit).Offset(offset).
+		Find(&posts)
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to fetch posts: %w", result.Error)
+		logError(ctx, result.Error, "failed to fetch posts by tag")
+		return nil, fmt.Errorf("failed to fetch posts by tag: %w", result.Error)
 	}
 
-	return posts, nil
+	return &PostsByTagResult{
+		Posts:       posts,
+		CurrentPage: page,
+		TotalPages:  CountPostPages(total, limit),
+	}, nil
 }
 
-// DeletePost deletes a post by ID
-func (s *PostService) DeletePost(postID string) error {
-	result := s.DB.Delete(&models.Post{}, "id = ?", postID)
+// postTagQuery resolves tagName to the tag ID(s) it matches, scoping a
+// namespaced ("character:name") lookup to that type and treating the name
+// as a prefix.
+func (s *PostService) postTagQuery(tagName string) *gorm.DB {
+	tagQuery := s.DB.Model(&models.Tag{}).Select("id")
+	if typ, name, ok := strings.Cut(tagName, ":"); ok {
+		tagQuery = tagQuery.Where("type = ? AND name LIKE ?", typ, name+"%")
+	} else {
+		tagQuery = tagQuery.Where("name = ?", tagName)
+	}
+
+	return tagQuery
+}
+
+// CountPostPages converts a total row count and page size into the number
+// of pages it spans, rounding up so a partial final page still counts.
+func CountPostPages(total int64, limit int) int {
+	if limit < 1 {
+		return 0
+	}
+
+	return int((total + int64(limit) - 1) / int64(limit))
+}
+
+// AttachTags associates the named tags with a post, creating any tag that
+// does not already exist yet.
+func (s *PostService) AttachTags(ctx context.Context, postID string, tagNames []string) (*models.Post, error) {
+	var post models.Post
+	if result := s.DB.First(&post, "id = ?", postID); result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("post not found")
+		}
+		logError(ctx, result.Error, "failed to fetch post")
+		return nil, fmt.Errorf("failed to fetch post: %w", result.Error)
+	}
+
+	tags, err := s.findOrCreateTags(ctx, tagNames)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Model(&post).Association("Tags").Append(tags); err != nil {
+		logError(ctx, err, "failed to attach tags")
+		return nil, fmt.Errorf("failed to attach tags: %w", err)
+	}
+
+	return &post, nil
+}
+
+// DetachTags removes the named tags from a post without deleting the tags
+// themselves.
+func (s *PostService) DetachTags(ctx context.Context, postID string, tagNames []string) error {
+	var post models.Post
+	if result := s.DB.First(&post, "id = ?", postID); result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return errors.New("post not found")
+		}
+		logError(ctx, result.Error, "failed to fetch post")
+		return fmt.Errorf("failed to fetch post: %w", result.Error)
+	}
+
+	// Resolve names the way findOrCreateTags stores them, so namespaced
+	// ("character:name") tags can be detached too.
+	tagQuery := s.DB.Where("1 = 0")
+	for _, raw := range tagNames {
+		typ, name := "general", raw
+		if t, n, ok := strings.Cut(raw, ":"); ok {
+			typ, name = t, n
+		}
+		tagQuery = tagQuery.Or("(name = ? AND type = ?)", name, typ)
+	}
+
+	var tags []models.Tag
+	if result := tagQuery.Find(&tags); result.Error != nil {
+		logError(ctx, result.Error, "failed to fetch tags")
+		return fmt.Errorf("failed to fetch tags: %w", result.Error)
+	}
+
+	if err := s.DB.Model(&post).Association("Tags").Delete(tags); err != nil {
+		logError(ctx, err, "failed to detach tags")
+		return fmt.Errorf("failed to detach tags: %w", err)
+	}
+
+	return nil
+}
+
+// findOrCreateTags resolves tag names to Tag rows, creating any that do
+// not exist. A name may carry a booru-style namespace prefix
+// ("character:name"); tags without one default to the "general" type.
+func (s *PostService) findOrCreateTags(ctx context.Context, tagNames []string) ([]models.Tag, error) {
+	tags := make([]models.Tag, 0, len(tagNames))
+
+	for _, raw := range tagNames {
+		typ, name := "general", raw
+		if t, n, ok := strings.Cut(raw, ":"); ok {
+			typ, name = t, n
+		}
+
+		var tag models.Tag
+		result := s.DB.Where("name = ? AND type = ?", name, typ).FirstOrCreate(&tag, models.Tag{Name: name, Type: typ})
+		if result.Error != nil {
+			logError(ctx, result.Error, "failed to resolve tag")
+			return nil, fmt.Errorf("failed to resolve tag %q: %w", raw, result.Error)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// DeletePost deletes a post by ID. Only the post's owner or a user with
+// the "admin" role may perform the deletion.
+func (s *PostService) DeletePost(ctx context.Context, postID string, userID uuid.UUID) error {
+	var existingPost models.Post
+	result := s.DB.First(&existingPost, "id = ?", postID)
 	if result.Error != nil {
-		return fmt.Errorf("failed to delete post: %w", result.Error)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return errors.New("post not found")
+		}
+		logError(ctx, result.Error, "failed to fetch post")
+		return fmt.Errorf("failed to fetch post: %w", result.Error)
 	}
 
-	if result.RowsAffected == 0 {
-		return errors.New("post not found")
+	if err := s.authorizeOwner(existingPost, userID); err != nil {
+		return err
+	}
+
+	result = s.DB.Delete(&existingPost)
+	if result.Error != nil {
+		logError(ctx, result.Error, "failed to delete post")
+		return fmt.Errorf("failed to delete post: %w", result.Error)
 	}
 
 	return nil
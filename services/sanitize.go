@@ -0,0 +1,155 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"golang.org/x/net/html"
+)
+
+var imgSrcPattern = regexp.MustCompile(`(?i)<img[^>]*\ssrc=["']([^"']+)["']`)
+
+var allowedContentTags = map[string]struct{}{
+	"h1": {}, "h2": {}, "h3": {}, "h4": {},
+	"p": {}, "br": {}, "strong": {}, "em": {},
+	"ul": {}, "ol": {}, "li": {},
+	"blockquote": {}, "a": {}, "img": {},
+	"pre": {}, "code": {},
+}
+
+// SanitizeError reports that CreatePost/UpdatePost content used a tag or
+// image host outside the editor's allowlist. PostController maps it to a
+// 422 response.
+type SanitizeError struct {
+	Tag    string
+	Reason string
+}
+
+func (e *SanitizeError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("disallowed content: %s", e.Reason)
+	}
+	return fmt.Sprintf("disallowed tag: <%s>", e.Tag)
+}
+
+// ContentSanitizer validates and cleans the HTML produced by the
+// WYSIWYG post editor before it is persisted: headings, lists, links,
+// <pre><code> blocks and <img src> from an allowlisted host are kept,
+// everything else is rejected outright rather than silently stripped.
+// <img src> values are also canonicalized to an absolute allowedImageHost
+// URL: a relative src is resolved against uploadBaseURL, so it is still
+// persisted as a fully-qualified src.
+type ContentSanitizer struct {
+	policy           *bluemonday.Policy
+	allowedImageHost string
+	uploadBaseURL    string
+}
+
+// NewContentSanitizer builds a sanitizer that only accepts <img src> URLs
+// pointing at allowedImageHost, resolving relative src values against
+// uploadBaseURL (initializers.Cfg.UploadPublicBaseURL).
+func NewContentSanitizer(allowedImageHost, uploadBaseURL string) *ContentSanitizer {
+	policy := bluemonday.NewPolicy()
+	policy.AllowElements("h1", "h2", "h3", "h4", "p", "br", "strong", "em", "ul", "ol", "li", "blockquote", "pre", "code")
+	policy.AllowAttrs("href").OnElements("a")
+	policy.AllowStandardURLs()
+	policy.RequireNoFollowOnLinks(true)
+	policy.AllowAttrs("src").OnElements("img")
+	policy.AllowURLSchemes("https", "http")
+
+	return &ContentSanitizer{policy: policy, allowedImageHost: allowedImageHost, uploadBaseURL: uploadBaseURL}
+}
+
+// Sanitize rejects content using a tag outside the editor allowlist or an
+// <img src> outside allowedImageHost, rewrites surviving <img src> values
+// to their canonical absolute form, then runs what remains through the
+// bluemonday policy to strip any disallowed attributes.
+func (cs *ContentSanitizer) Sanitize(content string) (string, error) {
+	if tag, ok := firstDisallowedTag(content); ok {
+		return "", &SanitizeError{Tag: tag}
+	}
+
+	content, err := cs.canonicalizeImageSrcs(content)
+	if err != nil {
+		return "", err
+	}
+
+	return cs.policy.Sanitize(content), nil
+}
+
+// firstDisallowedTag walks content as real HTML (via golang.org/x/net/html's
+// tokenizer) and returns the first start/end tag outside allowedContentTags.
+// Tokenizing rather than regex-matching for "<" means prose and code like
+// "if a < b" is never mistaken for a tag.
+func firstDisallowedTag(content string) (string, bool) {
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return "", false
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			tag := strings.ToLower(string(name))
+			if _, ok := allowedContentTags[tag]; !ok {
+				return tag, true
+			}
+		}
+	}
+}
+
+// canonicalizeImageSrcs rewrites every <img src> in content to an absolute
+// URL on allowedImageHost, resolving paths relative to uploadBaseURL, and
+// rejects anything that still doesn't resolve onto allowedImageHost.
+func (cs *ContentSanitizer) canonicalizeImageSrcs(content string) (string, error) {
+	var rewriteErr error
+
+	rewritten := imgSrcPattern.ReplaceAllStringFunc(content, func(tag string) string {
+		if rewriteErr != nil {
+			return tag
+		}
+
+		match := imgSrcPattern.FindStringSubmatch(tag)
+		src := match[1]
+
+		canonical, err := cs.canonicalImageURL(src)
+		if err != nil {
+			rewriteErr = err
+			return tag
+		}
+
+		return strings.Replace(tag, src, canonical, 1)
+	})
+
+	if rewriteErr != nil {
+		return "", rewriteErr
+	}
+
+	return rewritten, nil
+}
+
+// canonicalImageURL resolves src against uploadBaseURL when it's relative,
+// then requires the result to point at allowedImageHost.
+func (cs *ContentSanitizer) canonicalImageURL(src string) (string, error) {
+	parsed, err := url.Parse(src)
+	if err != nil {
+		return "", &SanitizeError{Reason: fmt.Sprintf("image src %q is not a valid URL", src)}
+	}
+
+	if !parsed.IsAbs() {
+		base, err := url.Parse(cs.uploadBaseURL)
+		if err != nil {
+			return "", &SanitizeError{Reason: "image upload base URL is not a valid URL"}
+		}
+		parsed = base.ResolveReference(parsed)
+	}
+
+	if parsed.Hostname() != cs.allowedImageHost {
+		return "", &SanitizeError{Reason: fmt.Sprintf("image src %q is not served from an allowlisted host", src)}
+	}
+
+	return parsed.String(), nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+
+	"app/initializers"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/google/uuid"
+)
+
+// UploadService streams editor image uploads to whichever backend
+// initializers.Config selects and returns the public URL to store in post
+// content.
+type UploadService struct {
+	cfg *initializers.Config
+	s3  *s3.Client
+}
+
+// NewUploadService wires an UploadService off the loaded config. The S3
+// client is constructed eagerly, up front, when UploadBackend is "s3", so
+// concurrent uploads never race on initializing it.
+func NewUploadService(cfg *initializers.Config) *UploadService {
+	us := &UploadService{cfg: cfg}
+
+	if cfg.UploadBackend == "s3" {
+		us.s3 = s3.New(s3.Options{
+			Region:       cfg.S3Region,
+			BaseEndpoint: aws.String(cfg.S3Endpoint),
+			Credentials:  credentials.NewStaticCredentialsProvider(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+			UsePathStyle: true,
+		})
+	}
+
+	return us
+}
+
+// Upload streams file to the configured backend (local disk or an
+// S3-compatible store) and returns the URL it will be publicly reachable
+// at.
+func (us *UploadService) Upload(file multipart.File, header *multipart.FileHeader) (string, error) {
+	filename := fmt.Sprintf("%s%s", uuid.New().String(), filepath.Ext(header.Filename))
+
+	switch us.cfg.UploadBackend {
+	case "s3":
+		return us.uploadToS3(filename, file)
+	default:
+		return us.uploadToLocalDisk(filename, file)
+	}
+}
+
+func (us *UploadService) uploadToLocalDisk(filename string, file multipart.File) (string, error) {
+	if err := os.MkdirAll(us.cfg.UploadLocalDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to prepare upload directory: %w", err)
+	}
+
+	dst, err := os.Create(filepath.Join(us.cfg.UploadLocalDir, filename))
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload file: %w", err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, file); err != nil {
+		return "", fmt.Errorf("failed to write upload file: %w", err)
+	}
+
+	return fmt.Sprintf("%s/%s", us.cfg.UploadPublicBaseURL, filename), nil
+}
+
+func (us *UploadService) uploadToS3(filename string, file multipart.File) (string, error) {
+	_, err := us.s3.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(us.cfg.S3Bucket),
+		Key:    aws.String(filename),
+		Body:   file,
+		ACL:    types.ObjectCannedACLPublicRead,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload to s3: %w", err)
+	}
+
+	return fmt.Sprintf("%s/%s", us.cfg.UploadPublicBaseURL, filename), nil
+}